@@ -0,0 +1,54 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/core/types"
+	"github.com/ethereumproject/go-ethereum/plugins"
+)
+
+// FinalizeReceipt notifies plugins.Default of a receipt that has just been
+// produced by executing a transaction within a block. Once block processing
+// exists in this tree, it should be called once per transaction from
+// ApplyTransaction, right after the receipt is built and before it is
+// appended to the block's receipt list - that is the only point where the
+// receipt, its transaction and its containing block are all simultaneously
+// in scope, which is what would let an indexer plugin correlate the receipt
+// with where it actually lives on chain.
+//
+// This tree has no core/state_processor.go or ApplyTransaction yet, so
+// nothing calls FinalizeReceipt outside of core/hooks_test.go. It is the
+// intended call site for a future chunk that adds block processing, not a
+// live hook today - do not read its existence as evidence that receipts are
+// actually observed in production.
+func FinalizeReceipt(block *types.Block, tx *types.Transaction, receipt *types.Receipt) {
+	plugins.Default.FireOnNewReceipt(block, tx, receipt)
+}
+
+// FinalizeContractCode notifies plugins.Default of contract code as it is
+// loaded from the state trie. Once a state/trie layer exists in this tree,
+// it should be called from StateDB.GetCode right after a successful trie
+// lookup, so that a plugin observes exactly the code bytes backing a given
+// code hash, not bytes read from some other cache.
+//
+// This tree has no core/state/statedb.go yet, so nothing calls
+// FinalizeContractCode outside of core/hooks_test.go. Like FinalizeReceipt,
+// it is an intended call site awaiting a future chunk, not a live hook.
+func FinalizeContractCode(codeHash common.Hash, code []byte) {
+	plugins.Default.FireOnContractCode(codeHash, code)
+}
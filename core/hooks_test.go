@@ -0,0 +1,66 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/core/types"
+	"github.com/ethereumproject/go-ethereum/plugins"
+)
+
+// fakePlugin is an in-process stand-in for a loaded .so, used to prove that
+// FinalizeReceipt/FinalizeContractCode correctly dispatch to every
+// registered plugin. It does not prove anything calls them in production -
+// this tree has no block processing or state/trie layer yet, so they remain
+// unwired entry points outside of this test; see the doc comments in
+// core/hooks.go.
+type fakePlugin struct {
+	receipts     []*types.Receipt
+	contractCode int
+}
+
+func (p *fakePlugin) Name() string { return "fake" }
+
+func (p *fakePlugin) OnNewReceipt(block *types.Block, tx *types.Transaction, receipt *types.Receipt) {
+	p.receipts = append(p.receipts, receipt)
+}
+
+func (p *fakePlugin) OnContractCode(codeHash common.Hash, code []byte) {
+	p.contractCode++
+}
+
+var (
+	_ plugins.ReceiptHook      = (*fakePlugin)(nil)
+	_ plugins.ContractCodeHook = (*fakePlugin)(nil)
+)
+
+func TestFinalizeReceiptDispatchesToPlugins(t *testing.T) {
+	old := plugins.Default
+	defer func() { plugins.Default = old }()
+
+	p := &fakePlugin{}
+	plugins.Default = plugins.NewManager()
+	plugins.Default.Register(p)
+
+	receipt := &types.Receipt{Status: types.TxSuccess}
+	FinalizeReceipt(&types.Block{}, &types.Transaction{}, receipt)
+
+	if len(p.receipts) != 1 || p.receipts[0] != receipt {
+		t.Fatalf("expected FinalizeReceipt to dispatch the receipt to the plugin, got %v", p.receipts)
+	}
+}
+
+func TestFinalizeContractCodeDispatchesToPlugins(t *testing.T) {
+	old := plugins.Default
+	defer func() { plugins.Default = old }()
+
+	p := &fakePlugin{}
+	plugins.Default = plugins.NewManager()
+	plugins.Default.Register(p)
+
+	FinalizeContractCode(common.Hash{0x01}, []byte{0xde, 0xad})
+
+	if p.contractCode != 1 {
+		t.Fatalf("expected FinalizeContractCode to dispatch once, got %d", p.contractCode)
+	}
+}
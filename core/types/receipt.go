@@ -0,0 +1,263 @@
+// Copyright 2014 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/rlp"
+)
+
+// Receipt status codes, used both as the in-memory representation of a
+// receipt's outcome and, for Status, as the on-the-wire value under EIP-658.
+const (
+	// TxFailure is the status code of a failed transaction.
+	TxFailure = uint8(0)
+	// TxSuccess is the status code of a successful transaction.
+	TxSuccess = uint8(1)
+	// TxStatusUnknown is used internally to mark a Receipt that predates
+	// EIP-658 and therefore carries a PostState root instead of a Status.
+	// It is never a valid on-the-wire Status value.
+	TxStatusUnknown = uint8(0xFF)
+)
+
+// Receipt type identifiers, as introduced by EIP-2718. LegacyTxType receipts
+// are encoded exactly as they were before typed transactions existed; every
+// other type is wrapped in a type-prefixed envelope.
+const (
+	LegacyTxType     = uint8(0x00)
+	AccessListTxType = uint8(0x01)
+)
+
+// Receipt represents the results of a transaction.
+type Receipt struct {
+	// Consensus fields
+	PostState         []byte
+	Status            uint8
+	CumulativeGasUsed *big.Int
+	Bloom             Bloom
+	Logs              []*Log
+
+	// Type is the EIP-2718 receipt type. It is zero (LegacyTxType) for
+	// receipts that predate typed transactions and is not itself part of
+	// the legacy RLP encoding - it only governs how the receipt is wrapped
+	// on the wire and in the receipts trie.
+	Type uint8
+
+	// Implementation fields (not part of the consensus representation)
+	TxHash          common.Hash
+	ContractAddress common.Address
+	GasUsed         *big.Int
+}
+
+// receiptRLP is the RLP-serializable form of a Receipt's consensus fields,
+// shared by both the legacy and the EIP-2718 typed encodings. PostStateOrStatus
+// carries either the post-transaction state root (pre EIP-658) or the
+// single-byte EIP-658 status, and the ambiguity is resolved by the decoder
+// based on its length.
+//
+// Unlike Receipt itself, receiptRLP has no PostState/Status duality at the
+// wire level - PostStateOrStatus is just a byte slice - so its EncodeRLP and
+// DecodeRLP methods are mechanical and are generated by rlpgen from the
+// struct declaration below (whose fields carry no rlp tags) rather than
+// hand-written. See gen_receipt_rlp.go.
+//
+// The duality (and the typed-receipt envelope) genuinely cannot be expressed
+// through field tags, since it depends on the *length* of PostStateOrStatus
+// rather than its position in the list - RLP's "optional" tag only elides
+// trailing fields. That part of Receipt.EncodeRLP/DecodeRLP therefore stays
+// hand-written, with setFromRLP acting as the post-decode validation hook
+// that enforces the invariants the generator can't.
+//
+//go:generate go run github.com/ethereumproject/go-ethereum/rlp/rlpgen -type receiptRLP -out gen_receipt_rlp.go
+type receiptRLP struct {
+	PostStateOrStatus []byte
+	CumulativeGasUsed *big.Int
+	Bloom             Bloom
+	Logs              []*Log
+}
+
+// NewReceipt creates a barebone transaction receipt, copying the init fields.
+// Status starts out as TxStatusUnknown until it is explicitly set, so that a
+// receipt created without a root and never assigned a status is rejected by
+// EncodeRLP with an UnknownStatus error rather than silently treated as failed.
+func NewReceipt(root []byte, cumulativeGasUsed *big.Int) *Receipt {
+	return &Receipt{PostState: common.CopyBytes(root), CumulativeGasUsed: cumulativeGasUsed, Status: TxStatusUnknown}
+}
+
+// EncodeRLP implements rlp.Encoder, and flattens the consensus fields of a
+// receipt into an RLP stream. If r.Type is non-zero the result is an
+// EIP-2718 typed envelope: the type byte followed by the RLP encoding of the
+// consensus fields, wrapped as a single byte string.
+func (r *Receipt) EncodeRLP(w io.Writer) error {
+	data, err := r.encodePayload()
+	if err != nil {
+		return err
+	}
+	if r.Type == LegacyTxType {
+		_, err = w.Write(data)
+		return err
+	}
+	return rlp.Encode(w, append([]byte{r.Type}, data...))
+}
+
+// encodePayload resolves the PostState/Status ambiguity and RLP-encodes the
+// consensus fields of the receipt, independent of the typed envelope.
+func (r *Receipt) encodePayload() ([]byte, error) {
+	var postStateOrStatus []byte
+	switch {
+	case len(r.PostState) == common.HashLength:
+		// Pre EIP-658 receipt, PostState is the post-transaction state root.
+		postStateOrStatus = r.PostState
+	case len(r.PostState) == 0:
+		// EIP-658 receipt, PostState is replaced by Status.
+		if r.Status != TxSuccess && r.Status != TxFailure {
+			return nil, &ReceiptDecodeError{Field: "Status", Got: []byte{r.Status}, Reason: UnknownStatus}
+		}
+		postStateOrStatus = []byte{r.Status}
+	default:
+		return nil, &ReceiptDecodeError{Field: "PostState", Got: r.PostState, Reason: OversizedStatus}
+	}
+	return rlp.EncodeToBytes(&receiptRLP{postStateOrStatus, r.CumulativeGasUsed, r.Bloom, r.Logs})
+}
+
+// MarshalBinary returns the consensus encoding of the receipt, following the
+// same type-prefixed convention as Transaction.MarshalBinary: the legacy
+// encoding for Type == LegacyTxType, and a type-prefixed byte string for
+// every other type. This is the representation that is hashed into the
+// receipts trie, see DeriveSha.
+func (r *Receipt) MarshalBinary() ([]byte, error) {
+	if r.Type == LegacyTxType {
+		return r.encodePayload()
+	}
+	data, err := r.encodePayload()
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{r.Type}, data...), nil
+}
+
+// UnmarshalBinary decodes the consensus encoding of a receipt as produced by
+// MarshalBinary.
+func (r *Receipt) UnmarshalBinary(b []byte) error {
+	if len(b) == 0 {
+		return &ReceiptDecodeError{Field: "Type", Reason: TruncatedTypedEnvelope}
+	}
+	if b[0] > 0x7f {
+		// Legacy receipt, b is an RLP list.
+		return rlp.DecodeBytes(b, r)
+	}
+	// Typed receipt, b[0] is the type byte.
+	if !isValidReceiptType(b[0]) {
+		return &ReceiptDecodeError{Field: "Type", Got: b[:1], Reason: UnknownReceiptType}
+	}
+	var dec receiptRLP
+	if err := rlp.DecodeBytes(b[1:], &dec); err != nil {
+		return &ReceiptDecodeError{Field: "Type", Got: b[1:], Reason: TruncatedTypedEnvelope, wrapped: err}
+	}
+	r.Type = b[0]
+	return r.setFromRLP(dec)
+}
+
+// DecodeRLP implements rlp.Decoder, and loads the consensus fields of a
+// receipt from an RLP stream. A plain RLP list is a legacy receipt; a byte
+// string is an EIP-2718 typed receipt whose first byte is the type.
+func (r *Receipt) DecodeRLP(s *rlp.Stream) error {
+	kind, size, err := s.Kind()
+	if err != nil {
+		return err
+	}
+	switch kind {
+	case rlp.List:
+		var dec receiptRLP
+		if err := s.Decode(&dec); err != nil {
+			return err
+		}
+		r.Type = LegacyTxType
+		if err := r.setFromRLP(dec); err != nil {
+			return err
+		}
+	case rlp.Byte, rlp.String:
+		b, err := s.Bytes()
+		if err != nil {
+			return err
+		}
+		if len(b) == 0 {
+			return &ReceiptDecodeError{Field: "Type", Reason: TruncatedTypedEnvelope}
+		}
+		if !isValidReceiptType(b[0]) {
+			return &ReceiptDecodeError{Field: "Type", Got: b[:1], Reason: UnknownReceiptType}
+		}
+		var dec receiptRLP
+		if err := rlp.DecodeBytes(b[1:], &dec); err != nil {
+			return &ReceiptDecodeError{Field: "Type", Got: b[1:], Reason: TruncatedTypedEnvelope, wrapped: err}
+		}
+		r.Type = b[0]
+		if err := r.setFromRLP(dec); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid receipt encoding: expected list or typed-receipt string, got kind %d, size %d", kind, size)
+	}
+	return nil
+}
+
+func isValidReceiptType(t uint8) bool {
+	return t == LegacyTxType || t == AccessListTxType
+}
+
+// setFromRLP resolves the PostState/Status ambiguity coming out of the wire
+// format and populates the receipt's consensus fields.
+func (r *Receipt) setFromRLP(dec receiptRLP) error {
+	raw := dec.PostStateOrStatus
+	switch {
+	case len(raw) == common.HashLength:
+		r.PostState, r.Status = raw, TxStatusUnknown
+	case len(raw) == 1 && raw[0] == TxStatusUnknown:
+		return &ReceiptDecodeError{Field: "Status", Got: raw, Reason: UnknownStatus}
+	case len(raw) == 1 && (raw[0] == TxSuccess || raw[0] == TxFailure):
+		r.PostState, r.Status = nil, raw[0]
+	case len(raw) == 1:
+		return &ReceiptDecodeError{Field: "Status", Got: raw, Reason: UnknownStatus}
+	default:
+		return &ReceiptDecodeError{Field: "PostState", Got: raw, Reason: AmbiguousPostState}
+	}
+	r.CumulativeGasUsed, r.Bloom, r.Logs = dec.CumulativeGasUsed, dec.Bloom, dec.Logs
+	return nil
+}
+
+// Receipts implements DerivableList for receipts, so that DeriveSha can hash
+// a slice of receipts into the receipts trie. For typed receipts the binary
+// (type-prefixed) form is hashed rather than the plain RLP encoding, so the
+// trie root matches what every other client derives.
+type Receipts []*Receipt
+
+// Len returns the number of receipts in this list.
+func (rs Receipts) Len() int { return len(rs) }
+
+// GetRlp returns the consensus-encoding of the receipt at index i, suitable
+// for hashing into the receipts trie.
+func (rs Receipts) GetRlp(i int) []byte {
+	bytes, err := rs[i].MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	return bytes
+}
@@ -0,0 +1,127 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ReceiptDecodeReason classifies why a Receipt failed to encode or decode.
+type ReceiptDecodeReason int
+
+const (
+	// UnknownStatus means the EIP-658 Status byte was present but was
+	// neither TxFailure, TxSuccess, nor a value a future fork has defined.
+	UnknownStatus ReceiptDecodeReason = iota
+	// OversizedStatus means the PostState/Status field had a length other
+	// than 0 (EIP-658 Status follows) or common.HashLength (legacy root).
+	OversizedStatus
+	// AmbiguousPostState means the PostState/Status field's length doesn't
+	// let the decoder tell whether it held a root or a status.
+	AmbiguousPostState
+	// TruncatedTypedEnvelope means a typed receipt's type byte was valid
+	// but the RLP payload following it could not be decoded, or that the
+	// typed-receipt byte string was empty and had no type byte at all.
+	TruncatedTypedEnvelope
+	// UnknownReceiptType means the leading type byte of a typed receipt
+	// does not correspond to any receipt type this node knows about.
+	UnknownReceiptType
+)
+
+func (r ReceiptDecodeReason) String() string {
+	switch r {
+	case UnknownStatus:
+		return "UnknownStatus"
+	case OversizedStatus:
+		return "OversizedStatus"
+	case AmbiguousPostState:
+		return "AmbiguousPostState"
+	case TruncatedTypedEnvelope:
+		return "TruncatedTypedEnvelope"
+	case UnknownReceiptType:
+		return "UnknownReceiptType"
+	default:
+		return fmt.Sprintf("ReceiptDecodeReason(%d)", int(r))
+	}
+}
+
+// Sentinel errors for the reasons above, so callers can react with
+// errors.Is/errors.As instead of matching substrings of Error().
+var (
+	ErrUnknownStatus         = errors.New("unknown receipt status")
+	ErrInvalidPostStateLen   = errors.New("invalid receipt PostState length")
+	ErrAmbiguousPostState    = errors.New("ambiguous receipt PostState/Status encoding")
+	ErrTruncatedTypedReceipt = errors.New("truncated typed receipt envelope")
+	ErrUnknownReceiptType    = errors.New("unknown receipt type")
+)
+
+func (r ReceiptDecodeReason) sentinel() error {
+	switch r {
+	case UnknownStatus:
+		return ErrUnknownStatus
+	case OversizedStatus:
+		return ErrInvalidPostStateLen
+	case AmbiguousPostState:
+		return ErrAmbiguousPostState
+	case TruncatedTypedEnvelope:
+		return ErrTruncatedTypedReceipt
+	case UnknownReceiptType:
+		return ErrUnknownReceiptType
+	default:
+		return nil
+	}
+}
+
+// ReceiptDecodeError is returned by Receipt.EncodeRLP/DecodeRLP/UnmarshalBinary
+// when the receipt's consensus fields are malformed. It carries enough
+// structure for a caller to react programmatically - via Field/Got/Reason -
+// rather than parsing the message in Error(), and unwraps to a stable
+// sentinel so errors.Is(err, types.ErrUnknownStatus) works regardless of the
+// exact field values involved.
+type ReceiptDecodeError struct {
+	// Field is the struct field the error pertains to: "Status" or
+	// "PostState" for the legacy/EIP-658 duality, or "Type" for the
+	// EIP-2718 typed-receipt envelope.
+	Field string
+	// Got is the raw bytes that were rejected, if available.
+	Got []byte
+	// Reason is the machine-readable classification of the error.
+	Reason ReceiptDecodeReason
+
+	// wrapped is an optional underlying error (e.g. the RLP decode error
+	// for a TruncatedTypedEnvelope), included in Error() and reachable via
+	// errors.Is/errors.As.
+	wrapped error
+}
+
+func (e *ReceiptDecodeError) Error() string {
+	msg := fmt.Sprintf("invalid receipt %s (%s): %#x", e.Field, e.Reason, e.Got)
+	if e.wrapped != nil {
+		return fmt.Sprintf("%s: %v", msg, e.wrapped)
+	}
+	return msg
+}
+
+// Unwrap lets errors.Is/errors.As see through to the stable sentinel for
+// e.Reason, and from there to any underlying cause.
+func (e *ReceiptDecodeError) Unwrap() error {
+	if e.wrapped != nil {
+		return fmt.Errorf("%w: %w", e.Reason.sentinel(), e.wrapped)
+	}
+	return e.Reason.sentinel()
+}
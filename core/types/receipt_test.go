@@ -4,8 +4,8 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/hex"
+	"errors"
 	"math/big"
-	"strings"
 	"testing"
 
 	"github.com/ethereumproject/go-ethereum/common"
@@ -69,19 +69,28 @@ func TestInvalidReceiptsEncoding(t *testing.T) {
 	// case 1: invalid PostState
 	r := NewReceipt(make([]byte, 7), big.NewInt(4095))
 	_, err := encodeReceipt(r)
-	if err == nil {
-		t.Error("error was expected")
-	} else if !strings.Contains(err.Error(), "PostState") || !strings.Contains(err.Error(), "length") {
-		t.Error("probably invalid error message:", err)
+	var decErr *ReceiptDecodeError
+	if !errors.As(err, &decErr) {
+		t.Fatalf("expected a *ReceiptDecodeError, got %v (%T)", err, err)
+	}
+	if decErr.Field != "PostState" || decErr.Reason != OversizedStatus {
+		t.Errorf("unexpected error: %+v", decErr)
+	}
+	if !errors.Is(err, ErrInvalidPostStateLen) {
+		t.Errorf("expected errors.Is(err, ErrInvalidPostStateLen), got %v", err)
 	}
 
 	// case 2: no PostState (EIP-658), unknown transaction status
 	r = NewReceipt(nil, big.NewInt(4095))
 	_, err = encodeReceipt(r)
-	if err == nil {
-		t.Error("error was expected")
-	} else if !strings.Contains(err.Error(), "PostState") || !strings.Contains(err.Error(), "Status") || !strings.Contains(err.Error(), "unknown") {
-		t.Error("probably invalid error message:", err)
+	if !errors.As(err, &decErr) {
+		t.Fatalf("expected a *ReceiptDecodeError, got %v (%T)", err, err)
+	}
+	if decErr.Field != "Status" || decErr.Reason != UnknownStatus {
+		t.Errorf("unexpected error: %+v", decErr)
+	}
+	if !errors.Is(err, ErrUnknownStatus) {
+		t.Errorf("expected errors.Is(err, ErrUnknownStatus), got %v", err)
 	}
 }
 
@@ -114,30 +123,16 @@ func TestInvalidReceiptsDecoding(t *testing.T) {
 	invalid4 := "f9010A820101820fffb9010000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000c0"
 
 	testCases := []struct {
-		name              string
-		rlpHex            string
-		errorExpectations []string
+		name   string
+		rlpHex string
+		field  string
+		reason ReceiptDecodeReason
+		got    []byte
 	}{
-		{
-			"Status=0x22",
-			invalid1,
-			[]string{"Status", "22"},
-		},
-		{
-			"Status=0xEE",
-			invalid2,
-			[]string{"Status", "EE"},
-		},
-		{
-			"Status=0xFF(TxStatusUnknown)",
-			invalid3,
-			[]string{"Status", "FF"},
-		},
-		{
-			"Status=0x0101",
-			invalid4,
-			[]string{"PostState", "Status", "0101"},
-		},
+		{"Status=0x22", invalid1, "Status", UnknownStatus, []byte{0x22}},
+		{"Status=0xEE", invalid2, "Status", UnknownStatus, []byte{0xEE}},
+		{"Status=0xFF(TxStatusUnknown)", invalid3, "Status", UnknownStatus, []byte{0xFF}},
+		{"Status=0x0101", invalid4, "PostState", AmbiguousPostState, []byte{0x01, 0x01}},
 	}
 
 	for _, testCase := range testCases {
@@ -145,15 +140,74 @@ func TestInvalidReceiptsDecoding(t *testing.T) {
 			rlpData, _ := hex.DecodeString(testCase.rlpHex)
 			var r Receipt
 			err := r.DecodeRLP(rlp.NewStream(bytes.NewReader(rlpData), 0))
-			if err == nil {
-				t.Error("error was expected")
-			} else {
-				for _, e := range testCase.errorExpectations {
-					if !strings.Contains(err.Error(), e) {
-						tt.Error("Probably invalid error message:", err)
-					}
-				}
+			var decErr *ReceiptDecodeError
+			if !errors.As(err, &decErr) {
+				tt.Fatalf("expected a *ReceiptDecodeError, got %v (%T)", err, err)
+			}
+			if decErr.Field != testCase.field || decErr.Reason != testCase.reason {
+				tt.Errorf("unexpected error: %+v", decErr)
+			}
+			if !bytes.Equal(decErr.Got, testCase.got) {
+				tt.Errorf("unexpected Got: %X, want %X", decErr.Got, testCase.got)
 			}
 		})
 	}
 }
+
+func TestTypedReceiptRLPRoundTrip(t *testing.T) {
+	// An EIP-2930 access-list receipt (type 1) round-trips through both the
+	// RLP stream encoding and the MarshalBinary/UnmarshalBinary helpers.
+	r1 := NewReceipt(nil, big.NewInt(4095))
+	r1.Type = AccessListTxType
+	r1.Status = TxSuccess
+
+	rlpData, err := encodeReceipt(r1)
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+
+	var r2 Receipt
+	if err := r2.DecodeRLP(rlp.NewStream(bytes.NewReader(rlpData), 0)); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if r2.Type != r1.Type {
+		t.Errorf("invalid type: expected %v, got %v", r1.Type, r2.Type)
+	}
+	if r2.Status != r1.Status {
+		t.Errorf("invalid status: expected %v, got %v", r1.Status, r2.Status)
+	}
+
+	bin, err := r1.MarshalBinary()
+	if err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	var r3 Receipt
+	if err := r3.UnmarshalBinary(bin); err != nil {
+		t.Fatal("unexpected error", err)
+	}
+	if r3.Type != r1.Type || r3.Status != r1.Status {
+		t.Errorf("binary round trip mismatch: got type=%v status=%v", r3.Type, r3.Status)
+	}
+}
+
+func TestTypedReceiptRLPUnknownType(t *testing.T) {
+	// Type byte 0x02 is not (yet) a known receipt type.
+	rlpData, _ := hex.DecodeString("02")
+
+	var r Receipt
+	err := r.DecodeRLP(rlp.NewStream(bytes.NewReader(rlpData), 0))
+	if !errors.Is(err, ErrUnknownReceiptType) {
+		t.Errorf("expected errors.Is(err, ErrUnknownReceiptType), got %v", err)
+	}
+}
+
+func TestTypedReceiptRLPTruncatedBody(t *testing.T) {
+	// Type byte 0x01 (valid) followed by a truncated RLP body.
+	rlpData, _ := hex.DecodeString("8201c2")
+
+	var r Receipt
+	err := r.DecodeRLP(rlp.NewStream(bytes.NewReader(rlpData), 0))
+	if !errors.Is(err, ErrTruncatedTypedReceipt) {
+		t.Errorf("expected errors.Is(err, ErrTruncatedTypedReceipt), got %v", err)
+	}
+}
@@ -0,0 +1,311 @@
+// Copyright 2016 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/crypto"
+	"github.com/ethereumproject/go-ethereum/params"
+)
+
+// sigCache is used to cache the derived sender and the signer that produced
+// it, so that repeated calls to Transaction.From() under the same signer
+// don't re-run the expensive recovery.
+type sigCache struct {
+	signer Signer
+	from   common.Address
+}
+
+// Signer encapsulates transaction signature handling. The name of this type
+// is slightly misleading because Signers don't actually sign, they're just
+// for validating and processing of signatures.
+//
+// Note that this interface is not a stable API and may change at any time to
+// accommodate new protocol rules.
+type Signer interface {
+	// Sender returns the sender address of the transaction.
+	Sender(tx *Transaction) (common.Address, error)
+	// SignatureValues returns the raw R, S, V values corresponding to the
+	// given signature.
+	SignatureValues(tx *Transaction, sig []byte) (r, s, v *big.Int, err error)
+	// Hash returns the hash to be signed.
+	Hash(tx *Transaction) common.Hash
+	// ChainID returns the chain id this signer is bound to, or nil if the
+	// signer does not protect against cross-chain replay (HomesteadSigner).
+	ChainID() *big.Int
+	// Equal returns true if the given signer is the same as the receiver.
+	Equal(Signer) bool
+}
+
+// MakeSigner returns a Signer based on the given chain config and block
+// number, choosing the latest signer variant that the fork rules active at
+// that block support.
+func MakeSigner(config *params.ChainConfig, blockNumber *big.Int) Signer {
+	switch {
+	case config.IsEIP2930(blockNumber):
+		return NewEIP2930Signer(config.GetChainID())
+	case config.IsEIP155(blockNumber):
+		return NewEIP155Signer(config.GetChainID())
+	case config.IsHomestead(blockNumber):
+		return HomesteadSigner{}
+	default:
+		return FrontierSigner{}
+	}
+}
+
+// LatestSigner returns the 'most permissive' Signer available for the given
+// chain configuration, i.e. the one that accepts every transaction type the
+// config has ever enabled, irrespective of what block number is current.
+// Use this in transaction-creation code that doesn't know, or doesn't care,
+// about the current block number.
+func LatestSigner(config *params.ChainConfig) Signer {
+	if config.ChainID != nil {
+		if config.EIP2930Block != nil {
+			return NewEIP2930Signer(config.GetChainID())
+		}
+		if config.EIP155Block != nil {
+			return NewEIP155Signer(config.GetChainID())
+		}
+	}
+	return HomesteadSigner{}
+}
+
+// LatestSignerForChainID returns the 'most permissive' Signer for the given
+// chain id, i.e. the EIP-2930 signer since that is a superset of every
+// earlier signing scheme. Only use this in tests or when the chain config is
+// genuinely unavailable - prefer LatestSigner otherwise, since it correctly
+// falls back for chains that never enabled later signers.
+func LatestSignerForChainID(chainID *big.Int) Signer {
+	if chainID == nil {
+		return HomesteadSigner{}
+	}
+	return NewEIP2930Signer(chainID)
+}
+
+// SignTx signs the transaction using the given signer and private key.
+func SignTx(tx *Transaction, s Signer, prv *ecdsa.PrivateKey) (*Transaction, error) {
+	h := s.Hash(tx)
+	sig, err := crypto.Sign(h[:], prv)
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(s, sig)
+}
+
+// Sender returns the address derived from the signature (V, R, S) using
+// secp256k1 elliptic curve and an error if it failed deriving or upon an
+// incorrect signature.
+//
+// Sender caches the recovered address together with the signer that
+// produced it. The cache only holds one signer's result: a call with a
+// signer that is not Equal to the cached one recovers the address again and
+// replaces the cached entry, while subsequent calls with that signer are free.
+func Sender(signer Signer, tx *Transaction) (common.Address, error) {
+	if sc := tx.cachedSender(); sc != nil && sc.signer.Equal(signer) {
+		return sc.from, nil
+	}
+	addr, err := signer.Sender(tx)
+	if err != nil {
+		return common.Address{}, err
+	}
+	tx.setCachedSender(&sigCache{signer: signer, from: addr})
+	return addr, nil
+}
+
+// EIP2930Signer implements Signer using the access-list transaction (EIP-2930,
+// receipt type 1) rules: it accepts both legacy and typed transactions, and
+// falls back to EIP-155 replay protection for legacy ones.
+type EIP2930Signer struct {
+	EIP155Signer
+}
+
+// NewEIP2930Signer returns a signer that accepts EIP-2930 access-list
+// transactions in addition to everything EIP155Signer accepts.
+func NewEIP2930Signer(chainID *big.Int) EIP2930Signer {
+	return EIP2930Signer{NewEIP155Signer(chainID)}
+}
+
+func (s EIP2930Signer) ChainID() *big.Int { return s.chainID }
+
+func (s EIP2930Signer) Equal(s2 Signer) bool {
+	x, ok := s2.(EIP2930Signer)
+	return ok && x.chainID.Cmp(s.chainID) == 0
+}
+
+func (s EIP2930Signer) Sender(tx *Transaction) (common.Address, error) {
+	if tx.Type() != AccessListTxType {
+		return s.EIP155Signer.Sender(tx)
+	}
+	V, R, S := tx.RawSignatureValues()
+	return recoverPlain(s.Hash(tx), R, S, V, true)
+}
+
+func (s EIP2930Signer) SignatureValues(tx *Transaction, sig []byte) (R, S, V *big.Int, err error) {
+	if tx.Type() != AccessListTxType {
+		return s.EIP155Signer.SignatureValues(tx, sig)
+	}
+	R, S, _ = decodeSignature(sig)
+	V = big.NewInt(int64(sig[64]))
+	return R, S, V, nil
+}
+
+// Hash returns the hash to be signed by the sender. It does not uniquely
+// identify the transaction.
+func (s EIP2930Signer) Hash(tx *Transaction) common.Hash {
+	if tx.Type() != AccessListTxType {
+		return s.EIP155Signer.Hash(tx)
+	}
+	return prefixedRlpHash(AccessListTxType, []interface{}{
+		s.chainID,
+		tx.Nonce(),
+		tx.GasPrice(),
+		tx.Gas(),
+		tx.To(),
+		tx.Value(),
+		tx.Data(),
+		tx.AccessList(),
+	})
+}
+
+// EIP155Signer implements Signer using the EIP-155 rules.
+type EIP155Signer struct {
+	chainID, chainIDMul *big.Int
+}
+
+// NewEIP155Signer returns a signer that replay-protects transactions against
+// the given chain id.
+func NewEIP155Signer(chainID *big.Int) EIP155Signer {
+	if chainID == nil {
+		chainID = new(big.Int)
+	}
+	return EIP155Signer{
+		chainID:    chainID,
+		chainIDMul: new(big.Int).Mul(chainID, big.NewInt(2)),
+	}
+}
+
+func (s EIP155Signer) ChainID() *big.Int { return s.chainID }
+
+func (s EIP155Signer) Equal(s2 Signer) bool {
+	eip155, ok := s2.(EIP155Signer)
+	return ok && eip155.chainID.Cmp(s.chainID) == 0
+}
+
+func (s EIP155Signer) Sender(tx *Transaction) (common.Address, error) {
+	if tx.Type() != LegacyTxType {
+		return common.Address{}, ErrTxTypeNotSupported
+	}
+	if !tx.Protected() {
+		return HomesteadSigner{}.Sender(tx)
+	}
+	if tx.ChainID().Cmp(s.chainID) != 0 {
+		return common.Address{}, errors.New("invalid chain id for signer")
+	}
+	V, R, S := tx.RawSignatureValues()
+	V = new(big.Int).Sub(V, s.chainIDMul)
+	V.Sub(V, big.NewInt(8))
+	return recoverPlain(s.Hash(tx), R, S, V, true)
+}
+
+// SignatureValues returns a new set of R, S, V values correctly folded into
+// the EIP-155 replay-protected range for this signer's chain id.
+func (s EIP155Signer) SignatureValues(tx *Transaction, sig []byte) (R, S, V *big.Int, err error) {
+	R, S, V, err = HomesteadSigner{}.SignatureValues(tx, sig)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if s.chainID.Sign() != 0 {
+		V = big.NewInt(int64(sig[64] + 35))
+		V.Add(V, s.chainIDMul)
+	}
+	return R, S, V, nil
+}
+
+// Hash returns the hash to be signed by the sender. It does not uniquely
+// identify the transaction.
+func (s EIP155Signer) Hash(tx *Transaction) common.Hash {
+	return rlpHash([]interface{}{
+		tx.Nonce(),
+		tx.GasPrice(),
+		tx.Gas(),
+		tx.To(),
+		tx.Value(),
+		tx.Data(),
+		s.chainID, uint(0), uint(0),
+	})
+}
+
+// HomesteadSigner implements Signer interface using the homestead rules.
+type HomesteadSigner struct{ FrontierSigner }
+
+func (s HomesteadSigner) ChainID() *big.Int { return nil }
+
+func (s HomesteadSigner) Equal(s2 Signer) bool {
+	_, ok := s2.(HomesteadSigner)
+	return ok
+}
+
+func (hs HomesteadSigner) SignatureValues(tx *Transaction, sig []byte) (r, s, v *big.Int, err error) {
+	return hs.FrontierSigner.SignatureValues(tx, sig)
+}
+
+func (hs HomesteadSigner) Sender(tx *Transaction) (common.Address, error) {
+	v, r, s := tx.RawSignatureValues()
+	return recoverPlain(hs.Hash(tx), r, s, v, true)
+}
+
+// FrontierSigner implements Signer interface using the frontier rules.
+type FrontierSigner struct{}
+
+func (s FrontierSigner) ChainID() *big.Int { return nil }
+
+func (s FrontierSigner) Equal(s2 Signer) bool {
+	_, ok := s2.(FrontierSigner)
+	return ok
+}
+
+func (fs FrontierSigner) Sender(tx *Transaction) (common.Address, error) {
+	v, r, s := tx.RawSignatureValues()
+	return recoverPlain(fs.Hash(tx), r, s, v, false)
+}
+
+// SignatureValues returns signature values. This signature needs to be in
+// the [R || S || V] format where V is 0 or 1.
+func (fs FrontierSigner) SignatureValues(tx *Transaction, sig []byte) (r, s, v *big.Int, err error) {
+	return decodeSignature(sig)
+}
+
+// Hash returns the hash to be signed by the sender. It does not uniquely
+// identify the transaction.
+func (fs FrontierSigner) Hash(tx *Transaction) common.Hash {
+	return rlpHash([]interface{}{
+		tx.Nonce(),
+		tx.GasPrice(),
+		tx.Gas(),
+		tx.To(),
+		tx.Value(),
+		tx.Data(),
+	})
+}
+
+// ErrTxTypeNotSupported is returned when a signer is asked to handle a
+// transaction type it doesn't know how to process.
+var ErrTxTypeNotSupported = errors.New("transaction type not supported by this signer")
@@ -0,0 +1,65 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereumproject/go-ethereum/params"
+)
+
+func TestMakeSigner(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *params.ChainConfig
+		block  *big.Int
+		want   Signer
+	}{
+		{"frontier", &params.ChainConfig{}, big.NewInt(0), FrontierSigner{}},
+		{"homestead", &params.ChainConfig{HomesteadBlock: big.NewInt(0)}, big.NewInt(0), HomesteadSigner{}},
+		{
+			"eip155",
+			&params.ChainConfig{HomesteadBlock: big.NewInt(0), EIP155Block: big.NewInt(0), ChainID: big.NewInt(1)},
+			big.NewInt(0),
+			NewEIP155Signer(big.NewInt(1)),
+		},
+		{
+			"eip2930",
+			&params.ChainConfig{HomesteadBlock: big.NewInt(0), EIP155Block: big.NewInt(0), EIP2930Block: big.NewInt(0), ChainID: big.NewInt(1)},
+			big.NewInt(0),
+			NewEIP2930Signer(big.NewInt(1)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MakeSigner(tt.config, tt.block)
+			if !got.Equal(tt.want) {
+				t.Errorf("MakeSigner() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLatestSignerForChainID(t *testing.T) {
+	want := NewEIP2930Signer(big.NewInt(5))
+	if got := LatestSignerForChainID(big.NewInt(5)); !got.Equal(want) {
+		t.Errorf("LatestSignerForChainID() = %#v, want %#v", got, want)
+	}
+	if got := LatestSignerForChainID(nil); !got.Equal(HomesteadSigner{}) {
+		t.Errorf("LatestSignerForChainID(nil) = %#v, want HomesteadSigner", got)
+	}
+}
+
+func TestSignerEquality(t *testing.T) {
+	s1 := NewEIP155Signer(big.NewInt(1))
+	s2 := NewEIP155Signer(big.NewInt(1))
+	s3 := NewEIP155Signer(big.NewInt(2))
+	if !s1.Equal(s2) {
+		t.Error("expected signers with the same chain id to be equal")
+	}
+	if s1.Equal(s3) {
+		t.Error("expected signers with different chain ids to be unequal")
+	}
+	if s1.Equal(NewEIP2930Signer(big.NewInt(1))) {
+		t.Error("expected EIP155Signer and EIP2930Signer to be unequal")
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Command example is a minimal plugins.Plugin, built with
+// `go build -buildmode=plugin -o example.so .`. It counts every receipt it
+// sees and exposes a toy "example_receiptCount" RPC method, demonstrating
+// two of the hook points a plugin can use (ReceiptHook and
+// RPCNamespaceProvider).
+package main
+
+import (
+	"sync/atomic"
+
+	"github.com/ethereumproject/go-ethereum/core/types"
+	"github.com/ethereumproject/go-ethereum/plugins"
+)
+
+type examplePlugin struct {
+	count uint64
+}
+
+func (p *examplePlugin) Name() string { return "example" }
+
+func (p *examplePlugin) OnNewReceipt(block *types.Block, tx *types.Transaction, receipt *types.Receipt) {
+	atomic.AddUint64(&p.count, 1)
+}
+
+func (p *examplePlugin) RPCServices() map[string]interface{} {
+	return map[string]interface{}{"example": &exampleAPI{p}}
+}
+
+// exampleAPI is registered under the "example" namespace as
+// "example_receiptCount".
+type exampleAPI struct {
+	plugin *examplePlugin
+}
+
+func (a *exampleAPI) ReceiptCount() uint64 {
+	return atomic.LoadUint64(&a.plugin.count)
+}
+
+var (
+	_ plugins.ReceiptHook          = (*examplePlugin)(nil)
+	_ plugins.RPCNamespaceProvider = (*examplePlugin)(nil)
+)
+
+// PluginMain is the well-known entry point every plugin .so must export.
+func PluginMain() plugins.Plugin {
+	return &examplePlugin{}
+}
@@ -0,0 +1,45 @@
+package plugins
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// TestLoadDirExamplePlugin builds the example plugin in ./example into a
+// temporary directory and confirms the Manager can load it end-to-end via
+// the real plugin.Open path (as opposed to the in-process fakePlugin used by
+// the rest of this package's tests).
+func TestLoadDirExamplePlugin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin package does not support windows")
+	}
+
+	dir := t.TempDir()
+	soPath := filepath.Join(dir, "example.so")
+
+	cmd := exec.Command("go", "build", "-buildmode=plugin", "-o", soPath, ".")
+	cmd.Dir = "example"
+	cmd.Env = append(os.Environ(), "CGO_ENABLED=1")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Skipf("building example plugin: %v\n%s", err, out)
+	}
+
+	m := NewManager()
+	if err := m.LoadDir(dir); err != nil {
+		t.Fatalf("LoadDir failed: %v", err)
+	}
+	if len(m.plugins) != 1 {
+		t.Fatalf("expected 1 loaded plugin, got %d", len(m.plugins))
+	}
+	if got := m.plugins[0].Name(); got != "example" {
+		t.Fatalf("unexpected plugin name: %q", got)
+	}
+
+	services := m.plugins[0].(RPCNamespaceProvider).RPCServices()
+	if _, ok := services["example"]; !ok {
+		t.Fatalf("expected example plugin to register the %q RPC namespace", "example")
+	}
+}
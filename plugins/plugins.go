@@ -0,0 +1,202 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package plugins implements an optional hook subsystem that lets users drop
+// compiled Go plugins (built with `go build -buildmode=plugin`) into a
+// configured directory. Loaded plugins can observe canonical chain data
+// (new receipts, contract code as it is loaded) and register additional
+// JSON-RPC namespaces, without requiring any changes to geth itself.
+//
+// Hooks are meant to be fired from block-processing and state-trie call
+// sites - see core.FinalizeReceipt and core.FinalizeContractCode - rather
+// than from core/types' RLP codec, since a receipt only carries its block
+// hash and transaction hash in the context surrounding ApplyTransaction,
+// never in its own encoding. As of this package, though, this tree has no
+// ApplyTransaction or StateDB: core.FinalizeReceipt/FinalizeContractCode are
+// the intended call sites for a future chunk that adds block processing,
+// and until then are exercised only by their own tests, not by anything
+// that observes real chain activity.
+package plugins
+
+import (
+	"fmt"
+	"plugin"
+	"sync"
+
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/core/types"
+	"github.com/ethereumproject/go-ethereum/log"
+)
+
+// PluginMainSymbol is the exported symbol every plugin .so must provide. It
+// must resolve to a function with the signature `func() Plugin`.
+const PluginMainSymbol = "PluginMain"
+
+// RPCRegistrar is the subset of rpc.Server used to expose a plugin-provided
+// service under a JSON-RPC namespace.
+type RPCRegistrar interface {
+	RegisterName(namespace string, service interface{}) error
+}
+
+// Plugin is implemented by the value returned from a plugin's PluginMain
+// function. Name is the only required method; every hook is optional, since
+// a plugin may be interested in only a subset of the available hooks - the
+// Manager only calls a hook if the corresponding interface is implemented,
+// see ReceiptHook, ContractCodeHook and RPCNamespaceProvider below.
+type Plugin interface {
+	// Name identifies the plugin in logs.
+	Name() string
+}
+
+// ReceiptHook is implemented by plugins that want to observe every receipt
+// once block processing finalizes it, with the block and transaction it
+// belongs to.
+type ReceiptHook interface {
+	Plugin
+	OnNewReceipt(block *types.Block, tx *types.Transaction, receipt *types.Receipt)
+}
+
+// ContractCodeHook is implemented by plugins that want to observe contract
+// code as it is loaded from the state/trie layer.
+type ContractCodeHook interface {
+	Plugin
+	OnContractCode(codeHash common.Hash, code []byte)
+}
+
+// RPCNamespaceProvider is implemented by plugins that expose one or more
+// additional JSON-RPC namespaces.
+type RPCNamespaceProvider interface {
+	Plugin
+	// RPCServices returns the namespace->service pairs this plugin wants
+	// registered with the node's RPC server, e.g. {"myplugin": &myAPI{}}.
+	RPCServices() map[string]interface{}
+}
+
+// Manager loads plugins from a directory and dispatches hooks to them.
+type Manager struct {
+	mu      sync.RWMutex
+	plugins []Plugin
+}
+
+// NewManager returns an empty plugin manager. Use LoadDir to populate it.
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// LoadDir scans dir non-recursively for *.so files, opens each with
+// plugin.Open, resolves the PluginMainSymbol and registers the returned
+// Plugin. A plugin that fails to load is logged and skipped - a single bad
+// plugin must not prevent the node from starting.
+func (m *Manager) LoadDir(dir string) error {
+	entries, err := readDirSo(dir)
+	if err != nil {
+		return err
+	}
+	for _, path := range entries {
+		p, err := m.loadOne(path)
+		if err != nil {
+			log.Error("Failed to load plugin", "path", path, "err", err)
+			continue
+		}
+		log.Info("Loaded plugin", "path", path, "name", p.Name())
+	}
+	return nil
+}
+
+func (m *Manager) loadOne(path string) (Plugin, error) {
+	so, err := plugin.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening plugin: %v", err)
+	}
+	sym, err := so.Lookup(PluginMainSymbol)
+	if err != nil {
+		return nil, fmt.Errorf("looking up %s: %v", PluginMainSymbol, err)
+	}
+	main, ok := sym.(func() Plugin)
+	if !ok {
+		return nil, fmt.Errorf("%s has unexpected type %T, want func() Plugin", PluginMainSymbol, sym)
+	}
+	p := main()
+	m.mu.Lock()
+	m.plugins = append(m.plugins, p)
+	m.mu.Unlock()
+	return p, nil
+}
+
+// Register adds p to the manager directly, without going through LoadDir.
+// It exists for plugins that are linked into the binary rather than loaded
+// from a .so - e.g. in tests that exercise hook dispatch without building a
+// real plugin.
+func (m *Manager) Register(p Plugin) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.plugins = append(m.plugins, p)
+}
+
+// RegisterRPC registers every RPC namespace exposed by loaded plugins with
+// the given server.
+func (m *Manager) RegisterRPC(server RPCRegistrar) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, p := range m.plugins {
+		provider, ok := p.(RPCNamespaceProvider)
+		if !ok {
+			continue
+		}
+		for ns, service := range provider.RPCServices() {
+			if err := server.RegisterName(ns, service); err != nil {
+				return fmt.Errorf("registering RPC namespace %q from plugin %q: %v", ns, p.Name(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// FireOnNewReceipt invokes OnNewReceipt on every loaded plugin that
+// implements ReceiptHook. It is safe to call with a nil Manager (e.g. when
+// no plugin directory was configured) - the call becomes a no-op.
+func (m *Manager) FireOnNewReceipt(block *types.Block, tx *types.Transaction, receipt *types.Receipt) {
+	if m == nil {
+		return
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, p := range m.plugins {
+		if hook, ok := p.(ReceiptHook); ok {
+			hook.OnNewReceipt(block, tx, receipt)
+		}
+	}
+}
+
+// FireOnContractCode invokes OnContractCode on every loaded plugin that
+// implements ContractCodeHook. Safe to call with a nil Manager.
+func (m *Manager) FireOnContractCode(codeHash common.Hash, code []byte) {
+	if m == nil {
+		return
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, p := range m.plugins {
+		if hook, ok := p.(ContractCodeHook); ok {
+			hook.OnContractCode(codeHash, code)
+		}
+	}
+}
+
+// Default is the process-wide plugin manager used by the hook points in
+// core/hooks.go; core/types fires no hooks itself. It starts out empty (all hooks are
+// no-ops) until the node calls LoadDir during startup.
+var Default = NewManager()
@@ -0,0 +1,52 @@
+package plugins
+
+import (
+	"testing"
+
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/core/types"
+)
+
+// fakePlugin is an in-process stand-in for a loaded .so - it lets the tests
+// below exercise hook dispatch without going through the real plugin.Open
+// pipeline, which only TestLoadDirExamplePlugin in
+// example_integration_test.go does.
+type fakePlugin struct {
+	name        string
+	gotReceipts int
+}
+
+func (p *fakePlugin) Name() string { return p.name }
+
+func (p *fakePlugin) OnNewReceipt(block *types.Block, tx *types.Transaction, receipt *types.Receipt) {
+	p.gotReceipts++
+}
+
+func TestManagerFireOnNewReceiptDispatches(t *testing.T) {
+	m := NewManager()
+	p := &fakePlugin{name: "fake"}
+	m.Register(p)
+
+	m.FireOnNewReceipt(&types.Block{}, &types.Transaction{}, &types.Receipt{})
+	if p.gotReceipts != 1 {
+		t.Fatalf("expected OnNewReceipt to fire once, got %d", p.gotReceipts)
+	}
+}
+
+func TestManagerFireHooksNilSafe(t *testing.T) {
+	var m *Manager
+	// Hooks on a nil manager (no plugin directory configured) must be
+	// no-ops, not panics.
+	m.FireOnNewReceipt(&types.Block{}, &types.Transaction{}, &types.Receipt{})
+	m.FireOnContractCode(common.Hash{}, nil)
+}
+
+func TestReadDirSoMissingDir(t *testing.T) {
+	paths, err := readDirSo("/does/not/exist")
+	if err != nil {
+		t.Fatalf("unexpected error for missing directory: %v", err)
+	}
+	if len(paths) != 0 {
+		t.Fatalf("expected no paths, got %v", paths)
+	}
+}
@@ -0,0 +1,413 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"reflect"
+
+	"github.com/ethereumproject/go-ethereum/rlp/rlpstruct"
+)
+
+// This generator is a narrow first cut, not a general replacement for the
+// reflection-based codec: it only knows how to encode/decode *big.Int,
+// []byte, fixed-size byte arrays named in byteArrayIdents, and slices of a
+// type that encodes itself (detected by the IsEncoder heuristic in
+// typeOfExpr), plus the optional/tail/nil tag behavior in genEncode/
+// genDecode. It was built for, and is only exercised against,
+// core/types.receiptRLP (see gen_test.go). A struct that uses a field shape
+// outside that set - or a named byte-array type other than the ones listed
+// in byteArrayIdents - makes generate() fail with an explicit "unsupported
+// type" error rather than emit something that merely looks plausible;
+// extending coverage means teaching typeOfExpr/genEncode/genDecode about
+// the new shape, not just adding a -type flag and hoping.
+
+// structInfo holds everything the generator needs to know about the target
+// struct, extracted from its AST declaration.
+type structInfo struct {
+	pkgName string
+	name    string
+	fields  []rlpstruct.Field
+	tags    []rlpstruct.Tags
+}
+
+// loadStruct parses srcFile and extracts the struct named typeName.
+//
+// This is intentionally AST-based rather than go/types-based: it reads tags
+// and field names directly off the declaration, which is sufficient to
+// drive RLP codec generation and keeps rlpgen free of a go/packages
+// dependency. It does mean rlpgen cannot resolve a field's underlying kind
+// through type aliases - callers needing that should prefer simple,
+// concrete field types in structs that opt into generation, exactly as
+// core/types.receiptRLP does today.
+func loadStruct(srcFile, typeName string) (*structInfo, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, srcFile, nil, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %v", srcFile, err)
+	}
+
+	var decl *ast.StructType
+	for _, d := range f.Decls {
+		gd, ok := d.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok || ts.Name.Name != typeName {
+				continue
+			}
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				return nil, fmt.Errorf("%s.%s is not a struct type", f.Name.Name, typeName)
+			}
+			decl = st
+		}
+	}
+	if decl == nil {
+		return nil, fmt.Errorf("struct %s not found in %s", typeName, srcFile)
+	}
+
+	info := &structInfo{pkgName: f.Name.Name, name: typeName}
+	var allFields []rlpstruct.Field
+	index := 0
+	for _, field := range decl.Fields.List {
+		typ := typeOfExpr(field.Type)
+		var tag string
+		if field.Tag != nil {
+			tag = reflect.StructTag(trimBackticks(field.Tag.Value)).Get("rlp")
+		}
+		for _, name := range namesOf(field) {
+			allFields = append(allFields, rlpstruct.Field{
+				Name:     name,
+				Index:    index,
+				Exported: ast.IsExported(name),
+				Type:     typ,
+				Tag:      tag,
+			})
+			index++
+		}
+	}
+	fields, tags, err := rlpstruct.ProcessFields(allFields)
+	if err != nil {
+		return nil, err
+	}
+	info.fields, info.tags = fields, tags
+	return info, nil
+}
+
+func namesOf(f *ast.Field) []string {
+	if len(f.Names) == 0 {
+		return []string{exprString(f.Type)} // embedded field
+	}
+	names := make([]string, len(f.Names))
+	for i, n := range f.Names {
+		names[i] = n.Name
+	}
+	return names
+}
+
+func exprString(e ast.Expr) string {
+	var buf bytes.Buffer
+	_ = format.Node(&buf, token.NewFileSet(), e)
+	return buf.String()
+}
+
+// byteArrayIdents lists named types that loadStruct cannot see through to
+// their [N]byte declaration (since it never resolves identifiers, only their
+// spelling - see the doc comment above) but that genEncode/genDecode must
+// still treat as fixed-size byte arrays, e.g. core/types.Bloom.
+var byteArrayIdents = map[string]bool{
+	"Bloom": true,
+}
+
+// typeOfExpr derives an rlpstruct.Type for a field's AST type expression.
+// Like loadStruct as a whole, this is necessarily AST-based rather than
+// go/types-based: IsEncoder is a heuristic (a pointer to a named, non-builtin
+// type is assumed to implement rlp.Encoder, matching the convention that
+// struct slices feeding into a generated codec are always EncodeRLP-capable
+// types such as *Log), not a resolved interface check.
+func typeOfExpr(e ast.Expr) rlpstruct.Type {
+	name := exprString(e)
+	switch t := e.(type) {
+	case *ast.ArrayType:
+		elem := typeOfExpr(t.Elt)
+		if t.Len == nil {
+			return rlpstruct.Type{Name: name, Kind: reflect.Slice, Elem: &elem}
+		}
+		return rlpstruct.Type{Name: name, Kind: reflect.Array, Elem: &elem}
+	case *ast.StarExpr:
+		elem := typeOfExpr(t.X)
+		return rlpstruct.Type{Name: name, Kind: reflect.Ptr, Elem: &elem, IsEncoder: elem.Kind == reflect.Struct, IsDecoder: elem.Kind == reflect.Struct}
+	case *ast.Ident:
+		switch t.Name {
+		case "string":
+			return rlpstruct.Type{Name: name, Kind: reflect.String}
+		case "bool":
+			return rlpstruct.Type{Name: name, Kind: reflect.Bool}
+		case "byte", "uint8":
+			return rlpstruct.Type{Name: name, Kind: reflect.Uint8}
+		case "uint64":
+			return rlpstruct.Type{Name: name, Kind: reflect.Uint64}
+		case "big.Int":
+			return rlpstruct.Type{Name: name, Kind: reflect.Struct}
+		default:
+			if byteArrayIdents[t.Name] {
+				return rlpstruct.Type{Name: name, Kind: reflect.Array, Elem: &rlpstruct.Type{Name: "byte", Kind: reflect.Uint8}}
+			}
+			return rlpstruct.Type{Name: name, Kind: reflect.Struct}
+		}
+	case *ast.SelectorExpr:
+		// Qualified identifiers, e.g. big.Int.
+		if name == "big.Int" {
+			return rlpstruct.Type{Name: name, Kind: reflect.Struct}
+		}
+		return rlpstruct.Type{Name: name, Kind: reflect.Struct}
+	default:
+		return rlpstruct.Type{Name: name, Kind: reflect.Invalid}
+	}
+}
+
+func trimBackticks(s string) string {
+	if len(s) >= 2 && s[0] == '`' && s[len(s)-1] == '`' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// generate emits the Go source of the generated file: package clause,
+// "DO NOT EDIT" header, and EncodeRLP/DecodeRLP methods for info.
+func generate(info *structInfo) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by rlpgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", info.pkgName)
+	fmt.Fprintf(&buf, "import (\n\t\"io\"\n\n\t\"github.com/ethereumproject/go-ethereum/rlp\"\n)\n\n")
+
+	if err := genEncode(&buf, info); err != nil {
+		return nil, err
+	}
+	if err := genDecode(&buf, info); err != nil {
+		return nil, err
+	}
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return buf.Bytes(), fmt.Errorf("generated code does not compile: %v", err)
+	}
+	return out, nil
+}
+
+// isBigIntPtr reports whether f is a *big.Int field, the one pointer type
+// this generator knows how to write a nil-safe encoding for.
+func isBigIntPtr(f rlpstruct.Field) bool {
+	return f.Type.Kind == reflect.Ptr && f.Type.Elem != nil && f.Type.Elem.Name == "big.Int"
+}
+
+// isByteSlice reports whether f is a []byte (or equivalent) field.
+func isByteSlice(f rlpstruct.Field) bool {
+	return f.Type.Kind == reflect.Slice && f.Type.Elem != nil && f.Type.Elem.Kind == reflect.Uint8
+}
+
+// isByteArray reports whether f is a fixed-size byte array field, such as
+// core/types.Bloom.
+func isByteArray(f rlpstruct.Field) bool {
+	return f.Type.Kind == reflect.Array && f.Type.Elem != nil && f.Type.Elem.Kind == reflect.Uint8
+}
+
+// isEncoderSlice reports whether f is a slice of a type that encodes itself,
+// such as []*Log, which needs its own nested list rather than a single RLP
+// value per element.
+func isEncoderSlice(f rlpstruct.Field) bool {
+	return f.Type.Kind == reflect.Slice && f.Type.Elem != nil && f.Type.Elem.IsEncoder
+}
+
+// nonZeroExpr returns a boolean Go expression that is true when obj.<f.Name>
+// holds a non-zero value, used to decide whether a trailing optional field
+// needs to be written at all.
+func nonZeroExpr(f rlpstruct.Field) (string, error) {
+	switch {
+	case f.Type.Kind == reflect.Ptr:
+		return fmt.Sprintf("obj.%s != nil", f.Name), nil
+	case f.Type.Kind == reflect.Slice:
+		return fmt.Sprintf("len(obj.%s) > 0", f.Name), nil
+	case f.Type.Kind == reflect.String:
+		return fmt.Sprintf(`obj.%s != ""`, f.Name), nil
+	case f.Type.Kind == reflect.Bool:
+		return fmt.Sprintf("obj.%s", f.Name), nil
+	case f.Type.Kind == reflect.Uint8 || f.Type.Kind == reflect.Uint64:
+		return fmt.Sprintf("obj.%s != 0", f.Name), nil
+	default:
+		return "", fmt.Errorf("rlpgen: optional field %s has unsupported type %s", f.Name, f.Type.Name)
+	}
+}
+
+// emitEncodeField emits the statements that write a single, non-tail field's
+// value, assuming its "if optional, should I write at all" gate (if any) is
+// already open. indent is prepended to every emitted line.
+func emitEncodeField(buf *bytes.Buffer, indent string, f rlpstruct.Field, nilOK bool, nilKind rlpstruct.NilKind, tmp *int) error {
+	write := func(inner string, args ...interface{}) {
+		for _, line := range bytes.Split([]byte(fmt.Sprintf(inner, args...)), []byte("\n")) {
+			if len(line) > 0 {
+				fmt.Fprintf(buf, "%s%s\n", indent, line)
+			}
+		}
+	}
+	base := func() error {
+		switch {
+		case isBigIntPtr(f):
+			write("if obj.%s == nil {\nw.Write(rlp.EmptyString)\n} else {\nw.WriteBigInt(obj.%s)\n}", f.Name, f.Name)
+		case isByteSlice(f):
+			write("w.WriteBytes(obj.%s)", f.Name)
+		case isByteArray(f):
+			write("w.WriteBytes(obj.%s[:])", f.Name)
+		case isEncoderSlice(f):
+			elemVar := fmt.Sprintf("_tmp%d", *tmp)
+			*tmp++
+			write("%s := w.List()", elemVar)
+			write("for _, elem := range obj.%s {", f.Name)
+			write("\tif err := elem.EncodeRLP(w); err != nil {\n\t\treturn err\n\t}")
+			write("}")
+			write("w.ListEnd(%s)", elemVar)
+		default:
+			return fmt.Errorf("rlpgen: field %s has unsupported type %s for EncodeRLP generation", f.Name, f.Type.Name)
+		}
+		return nil
+	}
+	if !nilOK || (f.Type.Kind != reflect.Ptr && f.Type.Kind != reflect.Slice) {
+		return base()
+	}
+	marker := "rlp.EmptyString"
+	if nilKind == rlpstruct.NilKindList {
+		marker = "rlp.EmptyList"
+	}
+	write("if obj.%s == nil {", f.Name)
+	write("\tw.Write(%s)", marker)
+	write("} else {")
+	if err := base(); err != nil {
+		return err
+	}
+	write("}")
+	return nil
+}
+
+func genEncode(buf *bytes.Buffer, info *structInfo) error {
+	fmt.Fprintf(buf, "func (obj *%s) EncodeRLP(_w io.Writer) error {\n", info.name)
+	fmt.Fprintf(buf, "\tw := rlp.NewEncoderBuffer(_w)\n")
+
+	// Trailing optional fields may be elided from the wire format if they -
+	// and every optional field after them - hold a zero value. RLP has no
+	// way to skip a positional slot, so writing any field implies writing
+	// every optional field before it too; combined[i] ORs a field's own
+	// zero-check together with every later optional field's.
+	nonZero := make([]string, len(info.fields))
+	for i, f := range info.fields {
+		if !info.tags[i].Optional {
+			continue
+		}
+		expr, err := nonZeroExpr(f)
+		if err != nil {
+			return err
+		}
+		v := fmt.Sprintf("_nonzero%d", i)
+		fmt.Fprintf(buf, "\t%s := %s\n", v, expr)
+		nonZero[i] = v
+	}
+	combined := make([]string, len(info.fields))
+	acc := ""
+	for i := len(info.fields) - 1; i >= 0; i-- {
+		if nonZero[i] == "" {
+			continue
+		}
+		if acc == "" {
+			acc = nonZero[i]
+		} else {
+			acc = fmt.Sprintf("%s || %s", nonZero[i], acc)
+		}
+		combined[i] = acc
+	}
+
+	fmt.Fprintf(buf, "\t_tmp0 := w.List()\n")
+	tmp := 1
+	for i, f := range info.fields {
+		if info.tags[i].Tail {
+			if f.Type.Kind != reflect.Slice || f.Type.Elem == nil || !f.Type.Elem.IsEncoder {
+				return fmt.Errorf("rlpgen: tail field %s must be a slice of a self-encoding type", f.Name)
+			}
+			fmt.Fprintf(buf, "\tfor _, elem := range obj.%s {\n", f.Name)
+			fmt.Fprintf(buf, "\t\tif err := elem.EncodeRLP(w); err != nil {\n\t\t\treturn err\n\t\t}\n")
+			fmt.Fprintf(buf, "\t}\n")
+			continue
+		}
+		indent := "\t"
+		if combined[i] != "" {
+			fmt.Fprintf(buf, "\tif %s {\n", combined[i])
+			indent = "\t\t"
+		}
+		if err := emitEncodeField(buf, indent, f, info.tags[i].NilOK, info.tags[i].NilKind, &tmp); err != nil {
+			return err
+		}
+		if combined[i] != "" {
+			fmt.Fprintf(buf, "\t}\n")
+		}
+	}
+	fmt.Fprintf(buf, "\tw.ListEnd(_tmp0)\n")
+	fmt.Fprintf(buf, "\treturn w.Flush()\n}\n\n")
+	return nil
+}
+
+func genDecode(buf *bytes.Buffer, info *structInfo) error {
+	fmt.Fprintf(buf, "func (obj *%s) DecodeRLP(dec *rlp.Stream) error {\n", info.name)
+	fmt.Fprintf(buf, "\tif _, err := dec.List(); err != nil {\n\t\treturn err\n\t}\n")
+	for i, f := range info.fields {
+		tags := info.tags[i]
+		switch {
+		case tags.Tail:
+			if f.Type.Elem == nil {
+				return fmt.Errorf("rlpgen: tail field %s must be a slice", f.Name)
+			}
+			fmt.Fprintf(buf, "\tfor {\n")
+			fmt.Fprintf(buf, "\t\tif _, _, err := dec.Kind(); err == rlp.EOL {\n\t\t\tbreak\n\t\t} else if err != nil {\n\t\t\treturn err\n\t\t}\n")
+			fmt.Fprintf(buf, "\t\tvar elem %s\n", f.Type.Elem.Name)
+			fmt.Fprintf(buf, "\t\tif err := dec.Decode(&elem); err != nil {\n\t\t\treturn err\n\t\t}\n")
+			fmt.Fprintf(buf, "\t\tobj.%s = append(obj.%s, elem)\n", f.Name, f.Name)
+			fmt.Fprintf(buf, "\t}\n")
+		case tags.Optional:
+			fmt.Fprintf(buf, "\tif _, _, err := dec.Kind(); err == rlp.EOL {\n\t\treturn dec.ListEnd()\n\t} else if err != nil {\n\t\treturn err\n\t}\n")
+			fmt.Fprintf(buf, "\tif err := dec.Decode(&obj.%s); err != nil {\n\t\treturn err\n\t}\n", f.Name)
+		case tags.NilOK:
+			// The generic reflective decoder already knows how to turn an
+			// empty string/list into a nil pointer or slice - the nil tag
+			// only changes what EncodeRLP writes for a nil value, not what
+			// DecodeRLP needs to do to read one back.
+			fmt.Fprintf(buf, "\tif err := dec.Decode(&obj.%s); err != nil {\n\t\treturn err\n\t}\n", f.Name)
+		case isByteSlice(f):
+			fmt.Fprintf(buf, "\tb, err := dec.Bytes()\n\tif err != nil {\n\t\treturn err\n\t}\n\tobj.%s = b\n", f.Name)
+		case isBigIntPtr(f), isByteArray(f), isEncoderSlice(f):
+			fmt.Fprintf(buf, "\tif err := dec.Decode(&obj.%s); err != nil {\n\t\treturn err\n\t}\n", f.Name)
+		default:
+			return fmt.Errorf("rlpgen: field %s has unsupported type %s for DecodeRLP generation", f.Name, f.Type.Name)
+		}
+	}
+	fmt.Fprintf(buf, "\treturn dec.ListEnd()\n}\n")
+	return nil
+}
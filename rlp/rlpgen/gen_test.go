@@ -0,0 +1,55 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// receiptSrc and receiptGenerated locate core/types' receiptRLP struct and
+// its checked-in generated codec relative to this package, so this test
+// tracks the files even if the module is checked out elsewhere.
+const (
+	receiptSrc       = "../../core/types/receipt.go"
+	receiptGenerated = "../../core/types/gen_receipt_rlp.go"
+)
+
+// TestGenerateReceiptRLP runs the generator against the real receiptRLP
+// struct and checks the output matches the checked-in gen_receipt_rlp.go
+// byte-for-byte. If this test fails, either gen.go has drifted from what
+// gen_receipt_rlp.go actually needs, or gen_receipt_rlp.go is stale and
+// should be regenerated via `go generate ./core/types`.
+func TestGenerateReceiptRLP(t *testing.T) {
+	info, err := loadStruct(receiptSrc, "receiptRLP")
+	if err != nil {
+		t.Fatalf("loadStruct: %v", err)
+	}
+	got, err := generate(info)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	want, err := os.ReadFile(filepath.FromSlash(receiptGenerated))
+	if err != nil {
+		t.Fatalf("reading checked-in file: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("generated code does not match %s; got:\n%s\nwant:\n%s", receiptGenerated, got, want)
+	}
+}
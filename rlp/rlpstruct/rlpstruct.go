@@ -0,0 +1,166 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package rlpstruct implements struct-tag processing used by rlp.Encode,
+// rlp.Decode, and the rlpgen code generator. It is a separate package
+// (rather than living inside rlp directly) so that rlpgen, which runs as a
+// standalone command and cannot import rlp itself without an import cycle
+// through reflection, can reuse the exact same tag semantics as the runtime
+// encoder/decoder.
+package rlpstruct
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Field represents a struct field.
+type Field struct {
+	Name     string
+	Index    int
+	Exported bool
+	Type     Type
+	Tag      string
+}
+
+// Type represents the attributes of a Go type.
+type Type struct {
+	Name      string
+	Kind      reflect.Kind
+	IsEncoder bool // whether type implements rlp.Encoder
+	IsDecoder bool // whether type implements rlp.Decoder
+	Elem      *Type
+}
+
+// DefaultNilValue determines whether a nil pointer to t encodes/decodes as
+// an empty list or an empty string by default, absent an explicit
+// "nilList"/"nilString" tag.
+func (t Type) DefaultNilValue() NilKind {
+	if t.Kind == reflect.Array || t.Kind == reflect.Struct {
+		return NilKindList
+	}
+	return NilKindString
+}
+
+// NilKind is the RLP value encoded in place of a nil pointer.
+type NilKind uint8
+
+const (
+	NilKindString NilKind = 0x80
+	NilKindList   NilKind = 0xC0
+)
+
+// Tags represents struct tags.
+type Tags struct {
+	// rlp:"-" ignores fields.
+	Ignored bool
+	// rlp:"nil" / rlp:"nilList" / rlp:"nilString" appoints a nil pointer
+	// value to be encoded/decoded as empty string or empty list.
+	NilOK   bool
+	NilKind NilKind
+	// rlp:"optional" allows for a field to be missing in the input list.
+	// If this is set, all subsequent fields must also be optional.
+	Optional bool
+	// rlp:"tail" controls whether this field swallows additional list
+	// elements. It can only be set for the last field, which must be of
+	// slice type.
+	Tail bool
+}
+
+// ParseTag parses the struct tag on a field, returning the Tags value that
+// describes it. A bare "nil" (without a suffix) is a shorthand for typ's
+// default nil kind, see Type.DefaultNilValue.
+func ParseTag(typ Type, tag string) (Tags, error) {
+	tag = strings.TrimSpace(tag)
+	var ts Tags
+	for _, t := range strings.Split(tag, ",") {
+		switch t = strings.TrimSpace(t); t {
+		case "":
+			// empty tag is allowed for backwards-compatibility
+		case "-":
+			ts.Ignored = true
+		case "nil", "nilString", "nilList":
+			ts.NilOK = true
+			switch t {
+			case "nil":
+				ts.NilKind = typ.DefaultNilValue()
+			case "nilString":
+				ts.NilKind = NilKindString
+			case "nilList":
+				ts.NilKind = NilKindList
+			}
+		case "optional":
+			ts.Optional = true
+		case "tail":
+			ts.Tail = true
+		default:
+			return Tags{}, fmt.Errorf("rlp: unknown struct tag %q", t)
+		}
+	}
+	return ts, nil
+}
+
+// ProcessFields filters the given struct field list, returning the fields
+// that should be encoded/decoded, in order. It also validates the
+// optional/tail invariants that the generator and the runtime codec both
+// rely on: once a field is optional, every field after it must be optional
+// too, and "tail" may only be used on the last field, which must be a
+// slice.
+func ProcessFields(allFields []Field) ([]Field, []Tags, error) {
+	var fields []Field
+	var tags []Tags
+
+	lastPublic := lastPublicField(allFields)
+	sawOptional := false
+	for _, field := range allFields {
+		if !field.Exported {
+			continue
+		}
+		ts, err := ParseTag(field.Type, field.Tag)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ts.Ignored {
+			continue
+		}
+		if ts.Tail && field.Index != lastPublic {
+			return nil, nil, fmt.Errorf(`rlp: invalid struct tag "tail" for field %s (must be on the last field)`, field.Name)
+		}
+		if ts.Tail && field.Type.Kind != reflect.Slice {
+			return nil, nil, fmt.Errorf(`rlp: invalid struct tag "tail" for field %s (field type is not slice)`, field.Name)
+		}
+		if sawOptional && !ts.Optional && !ts.Tail {
+			return nil, nil, fmt.Errorf(`rlp: invalid struct tag "" for field %s (must be optional because preceding field is optional)`, field.Name)
+		}
+		if ts.Optional {
+			sawOptional = true
+		}
+		fields = append(fields, field)
+		tags = append(tags, ts)
+	}
+	return fields, tags, nil
+}
+
+func lastPublicField(fields []Field) int {
+	last := 0
+	for _, f := range fields {
+		if f.Exported {
+			last = f.Index
+		}
+	}
+	return last
+}
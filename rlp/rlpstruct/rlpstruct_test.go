@@ -0,0 +1,74 @@
+package rlpstruct
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		tag     string
+		want    Tags
+		wantErr bool
+	}{
+		{tag: "", want: Tags{}},
+		{tag: "-", want: Tags{Ignored: true}},
+		{tag: "optional", want: Tags{Optional: true}},
+		{tag: "tail", want: Tags{Tail: true}},
+		{tag: "nilString", want: Tags{NilOK: true, NilKind: NilKindString}},
+		{tag: "nilList", want: Tags{NilOK: true, NilKind: NilKindList}},
+		{tag: "bogus", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTag(Type{Kind: reflect.Slice}, tt.tag)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseTag(%q): expected error", tt.tag)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseTag(%q): unexpected error %v", tt.tag, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseTag(%q) = %+v, want %+v", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestProcessFieldsOptionalOrdering(t *testing.T) {
+	fields := []Field{
+		{Name: "A", Index: 0, Exported: true, Type: Type{Kind: reflect.Uint64}, Tag: ""},
+		{Name: "B", Index: 1, Exported: true, Type: Type{Kind: reflect.Uint64}, Tag: "optional"},
+		{Name: "C", Index: 2, Exported: true, Type: Type{Kind: reflect.Uint64}, Tag: ""},
+	}
+	if _, _, err := ProcessFields(fields); err == nil {
+		t.Fatal("expected an error: C follows optional field B but is not itself optional")
+	}
+}
+
+func TestProcessFieldsTailMustBeLast(t *testing.T) {
+	fields := []Field{
+		{Name: "A", Index: 0, Exported: true, Type: Type{Kind: reflect.Slice}, Tag: "tail"},
+		{Name: "B", Index: 1, Exported: true, Type: Type{Kind: reflect.Uint64}, Tag: ""},
+	}
+	if _, _, err := ProcessFields(fields); err == nil {
+		t.Fatal("expected an error: tail field A is not last")
+	}
+}
+
+func TestProcessFieldsSkipsIgnoredAndUnexported(t *testing.T) {
+	fields := []Field{
+		{Name: "A", Index: 0, Exported: true, Type: Type{Kind: reflect.Uint64}, Tag: ""},
+		{Name: "b", Index: 1, Exported: false, Type: Type{Kind: reflect.Uint64}, Tag: ""},
+		{Name: "C", Index: 2, Exported: true, Type: Type{Kind: reflect.Uint64}, Tag: "-"},
+	}
+	got, tags, err := ProcessFields(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Name != "A" || len(tags) != 1 {
+		t.Fatalf("expected only field A to survive, got %+v", got)
+	}
+}